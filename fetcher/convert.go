@@ -0,0 +1,125 @@
+package fetcher
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register webp decoding
+)
+
+const defaultJPEGQuality = 90
+
+// ConvertOptions controls optional re-encoding and resizing of a downloaded
+// image before it is written to disk.
+type ConvertOptions struct {
+	// Format is the output format: "", "keep" (the input format), "jpeg"
+	// or "png". "webp" is accepted for input but cannot be produced, since
+	// golang.org/x/image/webp only implements decoding: if resizing (or
+	// another conversion) is needed on a webp source and Format is "" or
+	// "keep", the output falls back to PNG instead of failing the photo.
+	Format string
+	// Quality is the JPEG quality (1-100). Zero uses defaultJPEGQuality.
+	Quality int
+	// MaxWidth and MaxHeight bound the output size; the image is scaled
+	// down to fit while preserving aspect ratio. Zero means unbounded.
+	MaxWidth  int
+	MaxHeight int
+}
+
+// needed reports whether applying o would do anything at all, so callers
+// can skip decoding images they are just going to pass through unchanged.
+func (o ConvertOptions) needed() bool {
+	return (o.Format != "" && o.Format != "keep") || o.MaxWidth > 0 || o.MaxHeight > 0
+}
+
+// apply decodes data, optionally resizes it to fit within MaxWidth/
+// MaxHeight and re-encodes it to the requested format. It returns the
+// (possibly unchanged) bytes to write and the file extension they should
+// be written with.
+func (o ConvertOptions) apply(data []byte, ext string) ([]byte, string, error) {
+	if !o.needed() {
+		return data, ext, nil
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+
+	if o.MaxWidth > 0 || o.MaxHeight > 0 {
+		img = fitToBounds(img, o.MaxWidth, o.MaxHeight)
+	}
+
+	outFormat := o.Format
+	if outFormat == "" || outFormat == "keep" {
+		outFormat = format
+	}
+
+	// golang.org/x/image/webp only decodes, so "keep" can't mean "re-encode
+	// as webp" the way it does for jpeg/png. Since the caller only asked to
+	// resize/re-quality, not change format, fall back to a lossless PNG
+	// rather than failing the whole photo.
+	if outFormat == "webp" && (o.Format == "" || o.Format == "keep") {
+		outFormat = "png"
+	}
+
+	var buf bytes.Buffer
+
+	switch outFormat {
+	case "jpeg", "jpg":
+		quality := o.Quality
+		if quality == 0 {
+			quality = defaultJPEGQuality
+		}
+
+		if err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", fmt.Errorf("encode jpeg: %w", err)
+		}
+
+		return buf.Bytes(), "jpg", nil
+	case "png":
+		if err = png.Encode(&buf, img); err != nil {
+			return nil, "", fmt.Errorf("encode png: %w", err)
+		}
+
+		return buf.Bytes(), "png", nil
+	case "webp":
+		return nil, "", fmt.Errorf("cannot encode webp: golang.org/x/image/webp only supports decoding")
+	default:
+		return nil, "", fmt.Errorf("unknown format %q", outFormat)
+	}
+}
+
+// fitToBounds scales img down with a Catmull-Rom resampler so it fits
+// within maxWidth x maxHeight, preserving aspect ratio. maxWidth or
+// maxHeight of zero leaves that dimension unbounded. img is returned
+// unchanged if it already fits.
+func fitToBounds(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	scale := 1.0
+	if maxWidth > 0 && w > maxWidth {
+		scale = min(scale, float64(maxWidth)/float64(w))
+	}
+
+	if maxHeight > 0 && h > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(h))
+	}
+
+	if scale >= 1.0 {
+		return img
+	}
+
+	dstW := int(float64(w) * scale)
+	dstH := int(float64(h) * scale)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+
+	return dst
+}
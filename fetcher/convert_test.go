@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"testing"
+)
+
+func TestFitToBoundsScalesDownPreservingAspect(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	got := fitToBounds(img, 100, 0)
+
+	b := got.Bounds()
+	if b.Dx() != 100 || b.Dy() != 50 {
+		t.Fatalf("got %dx%d, want 100x50", b.Dx(), b.Dy())
+	}
+}
+
+func TestFitToBoundsLeavesSmallerImageUnchanged(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 50, 50))
+
+	got := fitToBounds(img, 100, 100)
+
+	if got != image.Image(img) {
+		t.Fatal("expected the original image to be returned unchanged")
+	}
+}
+
+func TestFitToBoundsBoundsOnBothDimensions(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+
+	got := fitToBounds(img, 50, 40)
+
+	b := got.Bounds()
+	if b.Dx() != 50 || b.Dy() != 25 {
+		t.Fatalf("got %dx%d, want 50x25", b.Dx(), b.Dy())
+	}
+}
+
+func TestConvertOptionsApplyConvertsFormat(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("encode source jpeg: %s", err)
+	}
+
+	o := ConvertOptions{Format: "png"}
+
+	data, ext, err := o.apply(buf.Bytes(), "jpg")
+	if err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+
+	if ext != "png" {
+		t.Fatalf("got ext %q, want png", ext)
+	}
+
+	if _, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("decode converted image: %s", err)
+	}
+}
+
+func TestConvertOptionsApplyResizesWebpInputByFallingBackToPNG(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiny.lossless.webp")
+	if err != nil {
+		t.Fatalf("read fixture: %s", err)
+	}
+
+	o := ConvertOptions{MaxWidth: 20}
+
+	out, ext, err := o.apply(data, "webp")
+	if err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+
+	if ext != "png" {
+		t.Fatalf("got ext %q, want png", ext)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode resized image: %s", err)
+	}
+
+	if img.Bounds().Dx() != 20 {
+		t.Fatalf("got width %d, want 20", img.Bounds().Dx())
+	}
+}
+
+func TestConvertOptionsApplyRejectsExplicitWebpOutput(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiny.lossless.webp")
+	if err != nil {
+		t.Fatalf("read fixture: %s", err)
+	}
+
+	_, _, err = ConvertOptions{Format: "webp"}.apply(data, "webp")
+	if err == nil {
+		t.Fatal("expected an error when explicitly requesting webp output")
+	}
+}
+
+func TestConvertOptionsNeeded(t *testing.T) {
+	tests := []struct {
+		name string
+		o    ConvertOptions
+		want bool
+	}{
+		{name: "empty", o: ConvertOptions{}, want: false},
+		{name: "keep format", o: ConvertOptions{Format: "keep"}, want: false},
+		{name: "format change", o: ConvertOptions{Format: "png"}, want: true},
+		{name: "max width", o: ConvertOptions{MaxWidth: 100}, want: true},
+		{name: "max height", o: ConvertOptions{MaxHeight: 100}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.o.needed(); got != tt.want {
+			t.Errorf("%s: needed() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Package fetcher implements the producer/worker-pool pipeline that pages
+// through a photo source and downloads its photos to a destination
+// directory, resuming from and updating a local cache manifest as it goes.
+// It is shared by the CLI's one-shot mode and the HTTP server's background
+// jobs.
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ArtAndreev/pexels-photo-fetcher/source"
+)
+
+// Options configures a single Run.
+type Options struct {
+	SourceKind string // pexels, unsplash, photoprism, local
+	Key        string
+	BaseURL    string
+	Query      string
+	Dir        string // local source root
+	Dst        string // destination directory for downloaded photos
+	Workers    int
+	PerPage    int
+	MaxPages   int // 0 means unlimited
+
+	// Size is passed through to source.Config; see its doc.
+	Size string
+	// RateLimitThreshold is passed through to source.Config; see its doc.
+	RateLimitThreshold int
+	// Convert controls optional re-encoding/resizing of downloaded images.
+	Convert ConvertOptions
+}
+
+// Run pages through the configured source and downloads its photos into
+// opts.Dst, reporting progress through tracker (which may be nil). It
+// returns once the source is exhausted, opts.MaxPages is reached, ctx is
+// canceled, or an unrecoverable error occurs.
+func Run(ctx context.Context, opts Options, tracker *ProgressTracker) error {
+	if tracker == nil {
+		tracker = NewProgressTracker()
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 8
+	}
+
+	if err := os.MkdirAll(opts.Dst, 0755); err != nil {
+		return fmt.Errorf("create destination directory: %w", err)
+	}
+
+	manifest, err := loadManifest(opts.Dst)
+	if err != nil {
+		return fmt.Errorf("load manifest: %w", err)
+	}
+
+	var client http.Client
+
+	src, err := source.New(opts.SourceKind, source.Config{
+		Client:             &client,
+		Key:                opts.Key,
+		BaseURL:            opts.BaseURL,
+		Query:              opts.Query,
+		PerPage:            opts.PerPage,
+		Size:               opts.Size,
+		Dir:                opts.Dir,
+		StartCursor:        manifest.NextPage,
+		RateLimitThreshold: opts.RateLimitThreshold,
+	})
+	if err != nil {
+		return fmt.Errorf("create source: %w", err)
+	}
+
+	if manifest.NextPage != "" {
+		log.Printf("resuming from saved cursor: %s", manifest.NextPage)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// workItem carries the per-page WaitGroup its photo belongs to, so the
+	// producer can tell when every photo dispatched from a page has
+	// actually finished processing before persisting that page's resume
+	// cursor (see the workItem.pageWG.Done() call in the worker loop).
+	type workItem struct {
+		photo  source.Photo
+		pageWG *sync.WaitGroup
+	}
+
+	photoCh := make(chan workItem, workers*2)
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		runErr  error
+	)
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			runErr = err
+			tracker.addError(err)
+			cancel()
+		})
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for item := range photoCh {
+				size, err := processPhoto(ctx, src, item.photo, opts.Dst, manifest, opts.Convert)
+				if err != nil {
+					fail(fmt.Errorf("failed to process photo %s: %w", item.photo.DownloadURL, err))
+				} else {
+					tracker.addPhoto(size)
+				}
+
+				item.pageWG.Done()
+			}
+		}()
+	}
+
+	totalCount := 0
+	pages := 0
+
+produce:
+	for {
+		photos, err := src.Next(ctx)
+		if err != nil {
+			fail(fmt.Errorf("fetch next page: %w", err))
+
+			break produce
+		}
+
+		if len(photos) == 0 {
+			break
+		}
+
+		var pageWG sync.WaitGroup
+
+		for _, p := range photos {
+			pageWG.Add(1)
+
+			select {
+			case photoCh <- workItem{photo: p, pageWG: &pageWG}:
+				totalCount++
+			case <-ctx.Done():
+				pageWG.Done()
+
+				break produce
+			}
+		}
+
+		// Wait for every photo dispatched from this page to finish before
+		// advancing the resume cursor past it, so a failure or
+		// cancellation mid-page leaves the cursor pointing at the page
+		// containing the still-incomplete photos rather than skipping them.
+		pageDone := make(chan struct{})
+
+		go func() {
+			pageWG.Wait()
+			close(pageDone)
+		}()
+
+		select {
+		case <-pageDone:
+		case <-ctx.Done():
+			break produce
+		}
+
+		pages++
+
+		log.Printf("fetched: %d", totalCount)
+
+		if err := manifest.setNextPage(src.Cursor()); err != nil {
+			fail(fmt.Errorf("save manifest: %w", err))
+
+			break produce
+		}
+
+		if opts.MaxPages > 0 && pages >= opts.MaxPages {
+			break
+		}
+	}
+
+	close(photoCh)
+	wg.Wait()
+
+	tracker.finish(runErr)
+
+	if runErr != nil {
+		return runErr
+	}
+
+	log.Printf("done, total count is %d", totalCount)
+
+	return nil
+}
+
+// processPhoto downloads a single photo unless the manifest shows it is
+// already cached on disk, optionally converting it per convert, and
+// returns the number of bytes freshly written.
+func processPhoto(
+	ctx context.Context, src source.Source, p source.Photo, dst string, m *manifest, convert ConvertOptions,
+) (int64, error) {
+	// Cut off query args
+	cleanURL := p.DownloadURL
+	if idx := strings.LastIndex(cleanURL, "?"); idx != -1 {
+		cleanURL = cleanURL[:idx]
+	}
+
+	base := path.Base(cleanURL)
+	ext := strings.TrimPrefix(filepath.Ext(base), ".")
+	nameNoExt := strings.TrimSuffix(base, filepath.Ext(base))
+
+	outExt := ext
+	if convert.Format != "" && convert.Format != "keep" {
+		outExt = convert.Format
+	}
+
+	fullPath := filepath.Join(dst, nameNoExt+"."+outExt)
+
+	if entry, ok := m.get(p.ID); ok && fileMatches(fullPath, entry) {
+		return 0, nil
+	}
+
+	img, err := src.Download(ctx, p)
+	if err != nil {
+		return 0, fmt.Errorf("download image: %w", err)
+	}
+
+	data, err := io.ReadAll(img)
+	img.Close()
+
+	if err != nil {
+		return 0, fmt.Errorf("read image: %w", err)
+	}
+
+	if data, outExt, err = convert.apply(data, ext); err != nil {
+		return 0, fmt.Errorf("convert image: %w", err)
+	}
+
+	fullPath = filepath.Join(dst, nameNoExt+"."+outExt)
+	tmpPath := fullPath + ".part"
+
+	if err = os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, fmt.Errorf("write file %s: %w", tmpPath, err)
+	}
+
+	if err = os.Rename(tmpPath, fullPath); err != nil {
+		return 0, fmt.Errorf("rename file %s: %w", tmpPath, err)
+	}
+
+	sum := sha256.Sum256(data)
+	entry := ManifestEntry{
+		URL:    p.DownloadURL,
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+
+	if err = m.recordPhoto(p.ID, entry); err != nil {
+		return 0, fmt.Errorf("record manifest: %w", err)
+	}
+
+	return int64(len(data)), nil
+}
@@ -0,0 +1,141 @@
+package fetcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// writeSourceFiles creates n small files named a.jpg, b.jpg, ... inside dir
+// and returns their names in sorted order (matching the local source's walk
+// order).
+func writeSourceFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	for i, name := range names {
+		data := []byte(strconv.Itoa(i) + "-" + name)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatalf("write source file %s: %s", name, err)
+		}
+	}
+}
+
+func TestRunLocalSourceHappyPath(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	writeSourceFiles(t, srcDir, "a.jpg", "b.jpg", "c.jpg")
+
+	opts := Options{
+		SourceKind: "local",
+		Dir:        srcDir,
+		Dst:        dstDir,
+		Workers:    2,
+	}
+
+	if err := Run(context.Background(), opts, nil); err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+
+	for _, name := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		if _, err := os.Stat(filepath.Join(dstDir, name)); err != nil {
+			t.Errorf("expected %s to be downloaded: %s", name, err)
+		}
+	}
+
+	m, err := loadManifest(dstDir)
+	if err != nil {
+		t.Fatalf("loadManifest: %s", err)
+	}
+
+	if len(m.Photos) != 3 {
+		t.Fatalf("got %d recorded photos, want 3", len(m.Photos))
+	}
+
+	if m.NextPage != "3" {
+		t.Fatalf("got NextPage %q, want %q", m.NextPage, "3")
+	}
+}
+
+func TestRunResumesFromSavedCursor(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	writeSourceFiles(t, srcDir, "a.jpg", "b.jpg")
+
+	opts := Options{
+		SourceKind: "local",
+		Dir:        srcDir,
+		Dst:        dstDir,
+		Workers:    2,
+	}
+
+	if err := Run(context.Background(), opts, nil); err != nil {
+		t.Fatalf("first Run: %s", err)
+	}
+
+	// A second Run against the same destination should resume from the
+	// saved cursor and find nothing left to do, rather than re-walking the
+	// source from the start.
+	tracker := NewProgressTracker()
+	if err := Run(context.Background(), opts, tracker); err != nil {
+		t.Fatalf("second Run: %s", err)
+	}
+
+	if got := tracker.Snapshot().PhotosFetched; got != 0 {
+		t.Fatalf("resumed run fetched %d photos, want 0", got)
+	}
+}
+
+func TestRunDoesNotRecordAPhotoThatFailsToBeWritten(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	// z_bad.jpg sorts last, so with a single worker it is guaranteed to be
+	// processed after a.jpg and b.jpg have already completed and been
+	// recorded, making the assertions below deterministic.
+	writeSourceFiles(t, srcDir, "a.jpg", "b.jpg", "z_bad.jpg")
+
+	// Collide the expected output path for z_bad.jpg with an existing
+	// directory, so processPhoto's final rename fails deterministically.
+	if err := os.Mkdir(filepath.Join(dstDir, "z_bad.jpg"), 0755); err != nil {
+		t.Fatalf("mkdir collision: %s", err)
+	}
+
+	opts := Options{
+		SourceKind: "local",
+		Dir:        srcDir,
+		Dst:        dstDir,
+		Workers:    1,
+	}
+
+	tracker := NewProgressTracker()
+
+	err := Run(context.Background(), opts, tracker)
+	if err == nil {
+		t.Fatal("expected Run to return an error")
+	}
+
+	snap := tracker.Snapshot()
+	if !snap.Failed {
+		t.Fatal("expected the progress snapshot to report Failed")
+	}
+
+	m, loadErr := loadManifest(dstDir)
+	if loadErr != nil {
+		t.Fatalf("loadManifest: %s", loadErr)
+	}
+
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		id := filepath.Join(srcDir, name)
+		if _, ok := m.get(id); !ok {
+			t.Errorf("expected %s (processed before the failure) to be recorded", name)
+		}
+	}
+
+	if _, ok := m.get(filepath.Join(srcDir, "z_bad.jpg")); ok {
+		t.Fatal("z_bad.jpg should not be recorded since its write failed")
+	}
+}
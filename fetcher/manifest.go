@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// manifestFileName is the name of the cache manifest written inside the
+// destination directory.
+const manifestFileName = ".photo-fetcher-manifest.json"
+
+// ManifestEntry records enough information about a completed download to
+// decide, on a later run, whether it can be skipped.
+type ManifestEntry struct {
+	URL    string `json:"url"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// manifest is a JSON-backed cache of completed downloads, keyed by photo ID,
+// plus the pagination cursor to resume from. It is safe for concurrent use.
+type manifest struct {
+	mu   sync.Mutex
+	path string
+
+	NextPage string                   `json:"next_page"`
+	Photos   map[string]ManifestEntry `json:"photos"`
+}
+
+// loadManifest reads the manifest from dst, returning an empty one if it
+// does not exist yet.
+func loadManifest(dst string) (*manifest, error) {
+	m := &manifest{
+		path:   filepath.Join(dst, manifestFileName),
+		Photos: make(map[string]ManifestEntry),
+	}
+
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+
+		return nil, fmt.Errorf("read manifest %s: %w", m.path, err)
+	}
+
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("unmarshal manifest %s: %w", m.path, err)
+	}
+
+	if m.Photos == nil {
+		m.Photos = make(map[string]ManifestEntry)
+	}
+
+	return m, nil
+}
+
+// ListPhotos returns the photos recorded in dst's manifest, for read-only
+// inspection (e.g. by the HTTP server).
+func ListPhotos(dst string) (map[string]ManifestEntry, error) {
+	m, err := loadManifest(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Photos, nil
+}
+
+// get returns the recorded entry for a photo ID, if any.
+func (m *manifest) get(id string) (ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.Photos[id]
+
+	return e, ok
+}
+
+// recordPhoto stores a completed download and persists the manifest.
+func (m *manifest) recordPhoto(id string, entry ManifestEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Photos[id] = entry
+
+	return m.saveLocked()
+}
+
+// setNextPage updates the resume cursor and persists the manifest.
+func (m *manifest) setNextPage(nextPage string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.NextPage = nextPage
+
+	return m.saveLocked()
+}
+
+// saveLocked writes the manifest to disk atomically. Callers must hold m.mu.
+func (m *manifest) saveLocked() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	tmpPath := m.path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("write manifest %s: %w", tmpPath, err)
+	}
+
+	if err = os.Rename(tmpPath, m.path); err != nil {
+		return fmt.Errorf("rename manifest %s: %w", tmpPath, err)
+	}
+
+	return nil
+}
+
+// fileMatches reports whether the file at fullPath already matches a
+// recorded manifest entry, so its download can be skipped.
+func fileMatches(fullPath string, entry ManifestEntry) bool {
+	info, err := os.Stat(fullPath)
+	if err != nil || info.Size() != entry.Size {
+		return false
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return false
+	}
+
+	return hex.EncodeToString(h.Sum(nil)) == entry.SHA256
+}
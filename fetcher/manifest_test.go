@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileMatches(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+
+	data := []byte("some photo bytes")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write file: %s", err)
+	}
+
+	sum := sha256.Sum256(data)
+	entry := ManifestEntry{Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+
+	if !fileMatches(path, entry) {
+		t.Fatal("expected a matching size and SHA256 to match")
+	}
+
+	wrongSum := sha256.Sum256([]byte("different bytes"))
+	mismatched := entry
+	mismatched.SHA256 = hex.EncodeToString(wrongSum[:])
+
+	if fileMatches(path, mismatched) {
+		t.Fatal("expected mismatch on wrong SHA256")
+	}
+
+	mismatched = entry
+	mismatched.Size = entry.Size + 1
+
+	if fileMatches(path, mismatched) {
+		t.Fatal("expected mismatch on wrong size")
+	}
+
+	if fileMatches(filepath.Join(dir, "missing.jpg"), entry) {
+		t.Fatal("expected mismatch for a missing file")
+	}
+}
+
+func TestFileMatchesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+
+	data := []byte("some photo bytes")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write file: %s", err)
+	}
+
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatalf("loadManifest: %s", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if err = m.recordPhoto("photo-1", ManifestEntry{Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}); err != nil {
+		t.Fatalf("recordPhoto: %s", err)
+	}
+
+	entry, ok := m.get("photo-1")
+	if !ok {
+		t.Fatal("expected recorded entry to be present")
+	}
+
+	if !fileMatches(path, entry) {
+		t.Fatal("expected the just-recorded entry to match the file on disk")
+	}
+}
+
+func TestManifestNextPagePersistsAcrossLoads(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatalf("loadManifest: %s", err)
+	}
+
+	if err = m.setNextPage("page-2"); err != nil {
+		t.Fatalf("setNextPage: %s", err)
+	}
+
+	reloaded, err := loadManifest(dir)
+	if err != nil {
+		t.Fatalf("loadManifest (reload): %s", err)
+	}
+
+	if reloaded.NextPage != "page-2" {
+		t.Fatalf("got NextPage %q, want %q", reloaded.NextPage, "page-2")
+	}
+}
+
+func TestLoadManifestMissingFileReturnsEmpty(t *testing.T) {
+	m, err := loadManifest(t.TempDir())
+	if err != nil {
+		t.Fatalf("loadManifest: %s", err)
+	}
+
+	if m.NextPage != "" || len(m.Photos) != 0 {
+		t.Fatalf("expected an empty manifest, got %+v", m)
+	}
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import "sync"
+
+// ProgressData is a point-in-time snapshot of a Run's progress.
+type ProgressData struct {
+	PhotosFetched int      `json:"photos_fetched"`
+	BytesWritten  int64    `json:"bytes_written"`
+	Errors        []string `json:"errors,omitempty"`
+	Done          bool     `json:"done"`
+	Failed        bool     `json:"failed"`
+}
+
+// ProgressTracker accumulates ProgressData updates from a running Run call
+// so it can be inspected concurrently, e.g. by an HTTP status handler.
+type ProgressTracker struct {
+	mu   sync.Mutex
+	data ProgressData
+}
+
+// NewProgressTracker returns an empty tracker.
+func NewProgressTracker() *ProgressTracker {
+	return &ProgressTracker{}
+}
+
+// Snapshot returns a copy of the current progress.
+func (t *ProgressTracker) Snapshot() ProgressData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data := t.data
+	data.Errors = append([]string(nil), t.data.Errors...)
+
+	return data
+}
+
+func (t *ProgressTracker) addPhoto(bytesWritten int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.data.PhotosFetched++
+	t.data.BytesWritten += bytesWritten
+}
+
+func (t *ProgressTracker) addError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.data.Errors = append(t.data.Errors, err.Error())
+}
+
+func (t *ProgressTracker) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.data.Done = true
+	t.data.Failed = err != nil
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtAndreev/pexels-photo-fetcher/fetcher"
+)
+
+// job tracks one background fetch started via POST /fetch.
+type job struct {
+	ID          string
+	Query       string
+	Destination string
+	CreatedAt   time.Time
+
+	cancel   context.CancelFunc
+	progress *fetcher.ProgressTracker
+}
+
+// jobView is the JSON shape returned by GET /jobs/{id}.
+type jobView struct {
+	ID          string    `json:"id"`
+	Query       string    `json:"query"`
+	Destination string    `json:"destination"`
+	CreatedAt   time.Time `json:"created_at"`
+	fetcher.ProgressData
+}
+
+func (j *job) view() jobView {
+	return jobView{
+		ID:           j.ID,
+		Query:        j.Query,
+		Destination:  j.Destination,
+		CreatedAt:    j.CreatedAt,
+		ProgressData: j.progress.Snapshot(),
+	}
+}
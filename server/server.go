@@ -0,0 +1,203 @@
+// Package server implements an HTTP front-end for the fetcher pipeline:
+// POST /fetch starts a background job, GET /jobs/{id} reports its progress,
+// GET /jobs/{id}/photos lists what it has downloaded so far, and
+// GET /photos/{name} streams a cached file straight off disk.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArtAndreev/pexels-photo-fetcher/fetcher"
+)
+
+// Server dispatches the photo-fetcher REST API and owns the set of jobs
+// started through it.
+type Server struct {
+	base       fetcher.Options // defaults applied to every job unless overridden by the request
+	defaultDst string          // root served by GET /photos/{name}
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	nextID int
+}
+
+// New returns a Server that launches jobs using base as the default options
+// and serves cached files out of defaultDst.
+func New(base fetcher.Options, defaultDst string) *Server {
+	return &Server{
+		base:       base,
+		defaultDst: defaultDst,
+		jobs:       make(map[string]*job),
+	}
+}
+
+// Handler builds the router for the REST API described in the package doc.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	apiRoutes := map[string]http.Handler{
+		"/fetch":   http.HandlerFunc(s.handleFetch),
+		"/jobs/":   http.HandlerFunc(s.handleJobs),
+		"/photos/": http.StripPrefix("/photos/", http.FileServer(http.Dir(s.defaultDst))),
+	}
+
+	for pattern, handler := range apiRoutes {
+		mux.Handle(pattern, handler)
+	}
+
+	return mux
+}
+
+type fetchRequest struct {
+	Query       string `json:"query"`
+	PerPage     int    `json:"per_page"`
+	MaxPages    int    `json:"max_pages"`
+	Destination string `json:"destination"`
+}
+
+type fetchResponse struct {
+	ID string `json:"id"`
+}
+
+func (s *Server) handleFetch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var req fetchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("decode request: %s", err), http.StatusBadRequest)
+
+		return
+	}
+
+	if req.Destination == "" {
+		http.Error(w, "destination is required", http.StatusBadRequest)
+
+		return
+	}
+
+	dst, err := s.resolveDestination(req.Destination)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid destination: %s", err), http.StatusBadRequest)
+
+		return
+	}
+
+	opts := s.base
+	opts.Query = req.Query
+	opts.Dst = dst
+
+	if req.PerPage > 0 {
+		opts.PerPage = req.PerPage
+	}
+
+	if req.MaxPages > 0 {
+		opts.MaxPages = req.MaxPages
+	}
+
+	j := s.startJob(opts)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(fetchResponse{ID: j.ID}) //nolint:errcheck // best effort
+}
+
+// resolveDestination confines a client-supplied destination to a
+// subdirectory of s.defaultDst, rejecting any path (including one using
+// "..") that would resolve outside of it. The server has no
+// authentication, so without this an unauthenticated caller could make
+// the process write downloaded files to an arbitrary path on disk.
+func (s *Server) resolveDestination(dest string) (string, error) {
+	root, err := filepath.Abs(s.defaultDst)
+	if err != nil {
+		return "", fmt.Errorf("resolve root: %w", err)
+	}
+
+	joined := filepath.Join(root, dest)
+
+	rel, err := filepath.Rel(root, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("destination %q escapes root directory", dest)
+	}
+
+	return joined, nil
+}
+
+func (s *Server) startJob(opts fetcher.Options) *job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	s.nextID++
+	id := strconv.Itoa(s.nextID)
+	j := &job{
+		ID:          id,
+		Query:       opts.Query,
+		Destination: opts.Dst,
+		CreatedAt:   time.Now(),
+		cancel:      cancel,
+		progress:    fetcher.NewProgressTracker(),
+	}
+	s.jobs[id] = j
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		if err := fetcher.Run(ctx, opts, j.progress); err != nil {
+			log.Printf("job %s failed: %s", id, err)
+		}
+	}()
+
+	return j
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	id, sub, _ := strings.Cut(rest, "/")
+
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch sub {
+	case "":
+		json.NewEncoder(w).Encode(j.view()) //nolint:errcheck // best effort
+	case "photos":
+		photos, err := fetcher.ListPhotos(j.Destination)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list photos: %s", err), http.StatusInternalServerError)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(photos) //nolint:errcheck // best effort
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
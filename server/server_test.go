@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArtAndreev/pexels-photo-fetcher/fetcher"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	// Dir is empty, so any job's local-source Run finishes immediately
+	// with nothing to download.
+	base := fetcher.Options{SourceKind: "local", Dir: t.TempDir(), Workers: 1}
+
+	return New(base, root), root
+}
+
+func TestResolveDestination(t *testing.T) {
+	srv, root := newTestServer(t)
+
+	tests := []struct {
+		name    string
+		dest    string
+		wantErr bool
+	}{
+		{name: "simple subdir", dest: "job1", wantErr: false},
+		{name: "nested subdir", dest: "a/b/c", wantErr: false},
+		{name: "dot-dot escape", dest: "../escape", wantErr: true},
+		{name: "nested dot-dot escape", dest: "a/../../escape", wantErr: true},
+		// An absolute-looking destination is still confined under root,
+		// since filepath.Join treats it as just another path component.
+		{name: "absolute-looking path stays confined", dest: "/etc/cron.d", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srv.resolveDestination(tt.dest)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveDestination(%q) = %q, <nil>; want an error", tt.dest, got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveDestination(%q) returned error: %s", tt.dest, err)
+			}
+
+			rel, err := filepath.Rel(root, got)
+			if err != nil || rel == ".." || filepath.IsAbs(rel) {
+				t.Fatalf("resolveDestination(%q) = %q, which does not resolve under root %q", tt.dest, got, root)
+			}
+		})
+	}
+}
+
+func postFetch(t *testing.T, srv *Server, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request body: %s", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/fetch", bytes.NewReader(data))
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleFetchRejectsPathEscape(t *testing.T) {
+	srv, root := newTestServer(t)
+
+	rec := postFetch(t, srv, map[string]string{"destination": "../../etc/cron.d"})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d; body: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "etc")); err == nil {
+		t.Fatal("destination escape should not have created anything outside the root")
+	}
+}
+
+func TestHandleFetchAcceptsValidDestination(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	rec := postFetch(t, srv, map[string]string{"destination": "job1"})
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got status %d, want %d; body: %s", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+
+	var resp fetchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %s", err)
+	}
+
+	if resp.ID == "" {
+		t.Fatal("expected a non-empty job ID")
+	}
+}
+
+func TestHandleFetchRequiresDestination(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	rec := postFetch(t, srv, map[string]string{})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d; body: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
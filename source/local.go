@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// localSource is the Source backend that walks an existing directory tree
+// instead of calling a remote API; useful for reprocessing an already
+// downloaded set of photos (e.g. resizing or converting them).
+type localSource struct {
+	dir     string
+	files   []string
+	perPage int
+	idx     int
+}
+
+func newLocalSource(cfg Config) (Source, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("local: no directory provided")
+	}
+
+	var files []string
+
+	err := filepath.WalkDir(cfg.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("local: walk %s: %w", cfg.Dir, err)
+	}
+
+	sort.Strings(files)
+
+	perPage := cfg.PerPage
+	if perPage == 0 {
+		perPage = 80
+	}
+
+	idx := 0
+
+	if cfg.StartCursor != "" {
+		i, err := strconv.Atoi(cfg.StartCursor)
+		if err != nil {
+			return nil, fmt.Errorf("local: parse resume cursor %q: %w", cfg.StartCursor, err)
+		}
+
+		idx = i
+	}
+
+	return &localSource{dir: cfg.Dir, files: files, perPage: perPage, idx: idx}, nil
+}
+
+func (s *localSource) Next(ctx context.Context) ([]Photo, error) {
+	if s.idx >= len(s.files) {
+		return nil, nil
+	}
+
+	end := s.idx + s.perPage
+	if end > len(s.files) {
+		end = len(s.files)
+	}
+
+	batch := s.files[s.idx:end]
+	s.idx = end
+
+	photos := make([]Photo, len(batch))
+	for i, path := range batch {
+		photos[i] = Photo{
+			ID:          path,
+			PageURL:     path,
+			DownloadURL: path,
+		}
+	}
+
+	return photos, nil
+}
+
+func (s *localSource) Download(ctx context.Context, p Photo) (io.ReadCloser, error) {
+	f, err := os.Open(p.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", p.DownloadURL, err)
+	}
+
+	return f, nil
+}
+
+func (s *localSource) Cursor() string {
+	return strconv.Itoa(s.idx)
+}
@@ -0,0 +1,212 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// pexelsResponse, pexelsPhoto and pexelsSrc are the Pexels API's own DTOs;
+// pexelsSource translates them into the backend-agnostic Photo type.
+type (
+	pexelsResponse struct {
+		TotalResults int           `json:"total_results"`
+		Page         int           `json:"page"`
+		PerPage      int           `json:"per_page"`
+		Photos       []pexelsPhoto `json:"photos"`
+		NextPage     string        `json:"next_page"`
+	}
+
+	pexelsPhoto struct {
+		ID              int       `json:"id"`
+		Width           int       `json:"width"`
+		Height          int       `json:"height"`
+		URL             string    `json:"url"`
+		Photographer    string    `json:"photographer"`
+		PhotographerURL string    `json:"photographer_url"`
+		PhotographerID  int       `json:"photographer_id"`
+		Src             pexelsSrc `json:"src"`
+		Liked           bool      `json:"liked"`
+	}
+
+	pexelsSrc struct {
+		Original  string `json:"original"`
+		Large2x   string `json:"large2x"`
+		Large     string `json:"large"`
+		Medium    string `json:"medium"`
+		Small     string `json:"small"`
+		Portrait  string `json:"portrait"`
+		Landscape string `json:"landscape"`
+		Tiny      string `json:"tiny"`
+	}
+)
+
+// resolve returns the URL for the requested rendition, falling back to
+// Large2x (the tool's historical default) for an empty or unknown size.
+func (s pexelsSrc) resolve(size string) string {
+	switch size {
+	case "original":
+		return s.Original
+	case "large":
+		return s.Large
+	case "medium":
+		return s.Medium
+	case "small":
+		return s.Small
+	case "portrait":
+		return s.Portrait
+	case "landscape":
+		return s.Landscape
+	case "tiny":
+		return s.Tiny
+	default:
+		return s.Large2x
+	}
+}
+
+// pexelsSource is the Source backend for api.pexels.com.
+type pexelsSource struct {
+	client  *http.Client
+	key     string
+	size    string
+	nextURL string
+	done    bool
+	rl      *rateLimiter
+}
+
+func newPexelsSource(cfg Config) (Source, error) {
+	if cfg.Key == "" {
+		return nil, fmt.Errorf("pexels: no key provided")
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	nextURL := cfg.StartCursor
+	if nextURL == "" {
+		perPage := cfg.PerPage
+		if perPage == 0 {
+			perPage = 80
+		}
+
+		u, err := compileFirstPexelsURL(cfg.Query, perPage)
+		if err != nil {
+			return nil, err
+		}
+
+		nextURL = u
+	}
+
+	return &pexelsSource{
+		client:  client,
+		key:     cfg.Key,
+		size:    cfg.Size,
+		nextURL: nextURL,
+		rl:      newRateLimiter(cfg.RateLimitThreshold),
+	}, nil
+}
+
+func compileFirstPexelsURL(query string, perPage int) (string, error) {
+	const firstURL = "https://api.pexels.com/v1/search"
+
+	u, err := url.Parse(firstURL)
+	if err != nil {
+		return "", fmt.Errorf("parse first url %s: %w", firstURL, err)
+	}
+
+	q := u.Query()
+	q.Set("query", query)
+	q.Set("per_page", strconv.Itoa(perPage))
+	q.Set("page", "1")
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (s *pexelsSource) Next(ctx context.Context) ([]Photo, error) {
+	if s.done || s.nextURL == "" {
+		return nil, nil
+	}
+
+	if err := s.rl.waitIfNeeded(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.nextURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create new request: %w", err)
+	}
+
+	req.Header.Add("Authorization", s.key)
+
+	resp, err := doWithRetry(s.client, req, s.rl.update)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 response")
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var pr pexelsResponse
+
+	if err = json.Unmarshal(body, &pr); err != nil {
+		return nil, fmt.Errorf("unmarshal json: %w, body: %q", err, body)
+	}
+
+	s.nextURL = pr.NextPage
+	if s.nextURL == "" {
+		s.done = true
+	}
+
+	photos := make([]Photo, len(pr.Photos))
+	for i, p := range pr.Photos {
+		photos[i] = Photo{
+			ID:          strconv.Itoa(p.ID),
+			PageURL:     p.URL,
+			DownloadURL: p.Src.resolve(s.size),
+			Author:      p.Photographer,
+			AuthorURL:   p.PhotographerURL,
+			Width:       p.Width,
+			Height:      p.Height,
+		}
+	}
+
+	return photos, nil
+}
+
+func (s *pexelsSource) Download(ctx context.Context, p Photo) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.DownloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create new request: %w", err)
+	}
+
+	resp, err := doWithRetry(s.client, req, nil) //nolint:bodyclose // should be closed outside
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 response")
+	}
+
+	return resp.Body, nil
+}
+
+func (s *pexelsSource) Cursor() string {
+	return s.nextURL
+}
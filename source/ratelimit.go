@@ -0,0 +1,104 @@
+package source
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultRateLimitThreshold = 5
+
+// rateLimiter tracks the X-Ratelimit-* headers the Pexels API returns on
+// every response and makes callers wait out a reset once the remaining
+// quota drops below a threshold, instead of hammering the API until it
+// starts returning 429s.
+type rateLimiter struct {
+	threshold int
+
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	resetAt   time.Time
+	seen      bool
+}
+
+func newRateLimiter(threshold int) *rateLimiter {
+	if threshold <= 0 {
+		threshold = defaultRateLimitThreshold
+	}
+
+	return &rateLimiter{threshold: threshold}
+}
+
+// update records the rate-limit headers from a response, logging a warning
+// once the remaining quota is at or below the configured threshold.
+func (rl *rateLimiter) update(resp *http.Response) {
+	remaining, ok := parseIntHeader(resp.Header, "X-Ratelimit-Remaining")
+	if !ok {
+		return
+	}
+
+	limit, _ := parseIntHeader(resp.Header, "X-Ratelimit-Limit")
+
+	var resetAt time.Time
+	if reset, ok := parseIntHeader(resp.Header, "X-Ratelimit-Reset"); ok {
+		resetAt = time.Unix(int64(reset), 0)
+	}
+
+	rl.mu.Lock()
+	rl.seen = true
+	rl.limit = limit
+	rl.remaining = remaining
+	rl.resetAt = resetAt
+	rl.mu.Unlock()
+
+	if remaining <= rl.threshold {
+		log.Printf("pexels: rate limit nearly exhausted: %d/%d remaining, resets at %s", remaining, limit, resetAt)
+	}
+}
+
+// waitIfNeeded blocks until the rate limit resets if the last observed
+// remaining quota was at or below the threshold.
+func (rl *rateLimiter) waitIfNeeded(ctx context.Context) error {
+	rl.mu.Lock()
+	seen := rl.seen
+	remaining := rl.remaining
+	threshold := rl.threshold
+	resetAt := rl.resetAt
+	rl.mu.Unlock()
+
+	if !seen || remaining > threshold {
+		return nil
+	}
+
+	wait := time.Until(resetAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	log.Printf("pexels: rate limit low (%d remaining), sleeping %s until reset", remaining, wait)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+func parseIntHeader(h http.Header, key string) (int, bool) {
+	v := h.Get(key)
+	if v == "" {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
@@ -0,0 +1,100 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterUpdate(t *testing.T) {
+	rl := newRateLimiter(5)
+
+	resetAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set("X-Ratelimit-Limit", "200")
+	resp.Header.Set("X-Ratelimit-Remaining", "3")
+	resp.Header.Set("X-Ratelimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+	rl.update(resp)
+
+	rl.mu.Lock()
+	limit, remaining, got := rl.limit, rl.remaining, rl.resetAt
+	rl.mu.Unlock()
+
+	if limit != 200 || remaining != 3 || !got.Equal(resetAt) {
+		t.Fatalf("got limit=%d remaining=%d resetAt=%s; want limit=200 remaining=3 resetAt=%s",
+			limit, remaining, got, resetAt)
+	}
+}
+
+func TestRateLimiterUpdateIgnoresMissingHeaders(t *testing.T) {
+	rl := newRateLimiter(5)
+
+	rl.update(&http.Response{Header: make(http.Header)})
+
+	rl.mu.Lock()
+	seen := rl.seen
+	rl.mu.Unlock()
+
+	if seen {
+		t.Fatal("update with no rate-limit headers should not mark the limiter as seen")
+	}
+}
+
+func TestRateLimiterWaitIfNeeded(t *testing.T) {
+	t.Run("below threshold returns immediately", func(t *testing.T) {
+		rl := newRateLimiter(5)
+
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set("X-Ratelimit-Remaining", "50")
+		rl.update(resp)
+
+		start := time.Now()
+		if err := rl.waitIfNeeded(context.Background()); err != nil {
+			t.Fatalf("waitIfNeeded returned error: %s", err)
+		}
+
+		if time.Since(start) > 100*time.Millisecond {
+			t.Fatal("waitIfNeeded blocked when remaining was above threshold")
+		}
+	})
+
+	t.Run("past reset time returns immediately", func(t *testing.T) {
+		rl := newRateLimiter(5)
+
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set("X-Ratelimit-Remaining", "1")
+		resp.Header.Set("X-Ratelimit-Reset", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10))
+		rl.update(resp)
+
+		start := time.Now()
+		if err := rl.waitIfNeeded(context.Background()); err != nil {
+			t.Fatalf("waitIfNeeded returned error: %s", err)
+		}
+
+		if time.Since(start) > 100*time.Millisecond {
+			t.Fatal("waitIfNeeded blocked on a reset time already in the past")
+		}
+	})
+}
+
+func TestParseIntHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Set("X-Present", "42")
+
+	if v, ok := parseIntHeader(h, "X-Present"); !ok || v != 42 {
+		t.Fatalf("got %d, %v; want 42, true", v, ok)
+	}
+
+	if _, ok := parseIntHeader(h, "X-Absent"); ok {
+		t.Fatal("expected ok=false for a missing header")
+	}
+
+	h.Set("X-NotAnInt", "nope")
+
+	if _, ok := parseIntHeader(h, "X-NotAnInt"); ok {
+		t.Fatal("expected ok=false for a non-integer header")
+	}
+}
@@ -0,0 +1,101 @@
+package source
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxRetries  = 5
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 30 * time.Second
+)
+
+// isRetryableStatus reports whether an HTTP status code indicates a
+// transient failure worth retrying.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// backoffDuration returns an exponential backoff delay with full jitter for
+// the given 0-based attempt, capped at maxBackoff.
+func backoffDuration(attempt int) time.Duration {
+	d := time.Duration(float64(baseBackoff) * math.Pow(2, float64(attempt)))
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// retryAfter parses a Retry-After header in its seconds form, returning ok
+// false when the header is absent or not a plain integer.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(secs) * time.Second, true
+}
+
+// doWithRetry sends req, retrying on network errors and retryable HTTP
+// status codes (429, 5xx) with exponential backoff and jitter. It honors a
+// Retry-After header when the server provides one. req must already carry
+// the desired context via http.NewRequestWithContext. If onResponse is not
+// nil, it is called with every response received, including ones that will
+// be retried, so callers can inspect headers such as a rate limit.
+func doWithRetry(client *http.Client, req *http.Request, onResponse func(*http.Response)) (*http.Response, error) {
+	ctx := req.Context()
+
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoffDuration(attempt - 1)):
+			}
+		}
+
+		resp, err := client.Do(req.Clone(ctx))
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		if onResponse != nil {
+			onResponse(resp)
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		lastErr = fmt.Errorf("got retryable status %d", resp.StatusCode)
+
+		wait, hasRetryAfter := retryAfter(resp.Header)
+		resp.Body.Close()
+
+		if hasRetryAfter {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("exhausted %d retries: %w", maxRetries, lastErr)
+}
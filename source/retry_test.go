@@ -0,0 +1,75 @@
+package source
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffDuration(attempt)
+		if d < 0 {
+			t.Fatalf("attempt %d: got negative duration %s", attempt, d)
+		}
+
+		if d > maxBackoff {
+			t.Fatalf("attempt %d: got %s, want <= maxBackoff (%s)", attempt, d, maxBackoff)
+		}
+	}
+}
+
+func TestBackoffDurationCapsAtMaxBackoff(t *testing.T) {
+	// baseBackoff * 2^10 comfortably exceeds maxBackoff.
+	d := backoffDuration(10)
+	if d > maxBackoff {
+		t.Fatalf("got %s, want <= maxBackoff (%s)", d, maxBackoff)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "absent", header: "", want: 0, wantOK: false},
+		{name: "seconds", header: "120", want: 120 * time.Second, wantOK: true},
+		{name: "zero", header: "0", want: 0, wantOK: true},
+		{name: "not an integer", header: "Wed, 21 Oct 2026 07:28:00 GMT", want: 0, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := make(http.Header)
+			if tt.header != "" {
+				h.Set("Retry-After", tt.header)
+			}
+
+			got, ok := retryAfter(h)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("retryAfter(%q) = %s, %v; want %s, %v", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.code); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
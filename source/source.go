@@ -0,0 +1,86 @@
+// Package source abstracts fetching and downloading photos from a
+// paginated photo API (or the local filesystem) so the rest of the tool
+// does not need to know which backend it is talking to.
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Photo is the backend-agnostic representation of a single photo, translated
+// from whatever DTO a given Source's API returns.
+type Photo struct {
+	ID          string
+	PageURL     string
+	DownloadURL string
+	Author      string
+	AuthorURL   string
+	Width       int
+	Height      int
+}
+
+// Source fetches pages of photos from a backend and downloads their
+// content. Implementations keep their own pagination state and must be safe
+// for one producer goroutine to call Next on while other goroutines call
+// Download concurrently.
+type Source interface {
+	// Next returns the next page of photos, or an empty slice once the
+	// backend is exhausted.
+	Next(ctx context.Context) ([]Photo, error)
+	// Download opens the photo's content for reading. The caller must
+	// close the returned reader.
+	Download(ctx context.Context, p Photo) (io.ReadCloser, error)
+	// Cursor returns an opaque, backend-specific resume token reflecting
+	// progress as of the last Next call. It can be round-tripped through
+	// Config.StartCursor to resume a later run.
+	Cursor() string
+}
+
+// Config carries the settings needed by any of the supported backends; a
+// given backend only looks at the fields relevant to it.
+type Config struct {
+	// Client is the HTTP client used for API and download requests.
+	Client *http.Client
+	// Key is the API key or token for pexels/unsplash/photoprism.
+	Key string
+	// BaseURL is the server root for backends without a fixed public API,
+	// e.g. photoprism.
+	BaseURL string
+	// Query is the search term for pexels/unsplash.
+	Query string
+	// Size selects which rendition of a photo to download on backends
+	// that offer more than one: original, large2x, large, medium, small,
+	// portrait, landscape or tiny. Empty uses the backend's default.
+	Size string
+	// PerPage is the page size requested from the backend, where
+	// applicable. Zero means use the backend's default.
+	PerPage int
+	// Dir is the directory walked by the local backend.
+	Dir string
+	// StartCursor resumes a backend from a previously saved Source.Cursor.
+	StartCursor string
+	// RateLimitThreshold is the remaining-quota level at or below which a
+	// rate-limit-aware backend (pexels) pauses until its quota resets.
+	// Zero uses defaultRateLimitThreshold.
+	RateLimitThreshold int
+}
+
+// New constructs the Source backend named by kind. Supported kinds are
+// "pexels", "unsplash", "photoprism" and "local".
+func New(kind string, cfg Config) (Source, error) {
+	switch kind {
+	case "pexels":
+		return newPexelsSource(cfg)
+	case "unsplash":
+		return newUnsplashSource(cfg)
+	case "photoprism":
+		return newPhotoPrismSource(cfg)
+	case "local":
+		return newLocalSource(cfg)
+	default:
+		return nil, fmt.Errorf("unknown source %q", kind)
+	}
+}
@@ -0,0 +1,199 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// unsplashResponse and unsplashPhoto are Unsplash's own search-endpoint
+// DTOs; unsplashSource translates them into the backend-agnostic Photo
+// type.
+type (
+	unsplashResponse struct {
+		Total      int             `json:"total"`
+		TotalPages int             `json:"total_pages"`
+		Results    []unsplashPhoto `json:"results"`
+	}
+
+	unsplashPhoto struct {
+		ID     string       `json:"id"`
+		Width  int          `json:"width"`
+		Height int          `json:"height"`
+		URLs   unsplashURLs `json:"urls"`
+		Links  struct {
+			HTML string `json:"html"`
+		} `json:"links"`
+		User struct {
+			Name  string `json:"name"`
+			Links struct {
+				HTML string `json:"html"`
+			} `json:"links"`
+		} `json:"user"`
+	}
+
+	unsplashURLs struct {
+		Raw     string `json:"raw"`
+		Full    string `json:"full"`
+		Regular string `json:"regular"`
+		Small   string `json:"small"`
+		Thumb   string `json:"thumb"`
+	}
+)
+
+// resolve maps the tool's backend-agnostic size names onto Unsplash's own
+// renditions, falling back to Full (the tool's historical default) for an
+// empty or unknown size.
+func (u unsplashURLs) resolve(size string) string {
+	switch size {
+	case "original":
+		return u.Raw
+	case "large":
+		return u.Regular
+	case "medium":
+		return u.Regular
+	case "small", "portrait", "landscape":
+		return u.Small
+	case "tiny":
+		return u.Thumb
+	default:
+		return u.Full
+	}
+}
+
+// unsplashSource is the Source backend for api.unsplash.com.
+type unsplashSource struct {
+	client   *http.Client
+	key      string
+	query    string
+	size     string
+	perPage  int
+	page     int
+	lastPage int
+	done     bool
+}
+
+func newUnsplashSource(cfg Config) (Source, error) {
+	if cfg.Key == "" {
+		return nil, fmt.Errorf("unsplash: no key provided")
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	perPage := cfg.PerPage
+	if perPage == 0 {
+		perPage = 30
+	}
+
+	page := 1
+
+	if cfg.StartCursor != "" {
+		p, err := strconv.Atoi(cfg.StartCursor)
+		if err != nil {
+			return nil, fmt.Errorf("unsplash: parse resume cursor %q: %w", cfg.StartCursor, err)
+		}
+
+		page = p
+	}
+
+	return &unsplashSource{client: client, key: cfg.Key, query: cfg.Query, size: cfg.Size, perPage: perPage, page: page}, nil
+}
+
+func (s *unsplashSource) Next(ctx context.Context) ([]Photo, error) {
+	if s.done {
+		return nil, nil
+	}
+
+	const searchURL = "https://api.unsplash.com/search/photos"
+
+	u, err := url.Parse(searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse search url %s: %w", searchURL, err)
+	}
+
+	q := u.Query()
+	q.Set("query", s.query)
+	q.Set("per_page", strconv.Itoa(s.perPage))
+	q.Set("page", strconv.Itoa(s.page))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create new request: %w", err)
+	}
+
+	req.Header.Add("Authorization", "Client-ID "+s.key)
+
+	resp, err := doWithRetry(s.client, req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 response")
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var ur unsplashResponse
+
+	if err = json.Unmarshal(body, &ur); err != nil {
+		return nil, fmt.Errorf("unmarshal json: %w, body: %q", err, body)
+	}
+
+	s.lastPage = ur.TotalPages
+	s.page++
+
+	if s.page > s.lastPage {
+		s.done = true
+	}
+
+	photos := make([]Photo, len(ur.Results))
+	for i, p := range ur.Results {
+		photos[i] = Photo{
+			ID:          p.ID,
+			PageURL:     p.Links.HTML,
+			DownloadURL: p.URLs.resolve(s.size),
+			Author:      p.User.Name,
+			AuthorURL:   p.User.Links.HTML,
+			Width:       p.Width,
+			Height:      p.Height,
+		}
+	}
+
+	return photos, nil
+}
+
+func (s *unsplashSource) Download(ctx context.Context, p Photo) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.DownloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create new request: %w", err)
+	}
+
+	resp, err := doWithRetry(s.client, req, nil) //nolint:bodyclose // should be closed outside
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 response")
+	}
+
+	return resp.Body, nil
+}
+
+func (s *unsplashSource) Cursor() string {
+	return strconv.Itoa(s.page)
+}